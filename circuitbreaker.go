@@ -1,8 +1,7 @@
 package circuitbreaker
 
 import (
-	"errors"
-	"sync"
+	"context"
 	"time"
 )
 
@@ -34,20 +33,31 @@ type CircuitBreaker interface {
 	// Execute runs the given function if the circuit allows it
 	Execute(fn func() (interface{}, error)) (interface{}, error)
 
+	// ExecuteContext runs fn if the circuit allows it, honoring ctx
+	// cancellation and, if Settings.CallTimeout is set, a per-call
+	// deadline derived from ctx.
+	ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error)
+
 	// State returns the current state of the circuit breaker
 	State() State
 
 	// Reset resets the circuit breaker to the initial closed state
 	Reset()
 
-	// Counts returns the current failure and success counts
+	// Counts returns the current consecutive failure and success counts
 	Counts() (failures int, successes int)
 }
 
 // Settings holds the configuration for the circuit breaker
 type Settings struct {
-	// FailureThreshold is the number of failures before the circuit opens
-	FailureThreshold int
+	// Name identifies the breaker. Optional. Nothing in this module reads
+	// it: metrics.Instrument labels its series with its own name argument.
+	Name string
+
+	// ReadyToTrip is called with the current Counts after every failure
+	// while Closed; it should return true to open the circuit. If nil,
+	// DefaultSettings' rule (5 consecutive failures) is used.
+	ReadyToTrip func(counts Counts) bool
 
 	// SuccessThreshold is the number of successes in half-open state before closing
 	SuccessThreshold int
@@ -58,6 +68,34 @@ type Settings struct {
 	// MaxRequests is the maximum number of requests allowed in half-open state
 	MaxRequests int
 
+	// Interval is how often Counts is cleared while the circuit is Closed.
+	// Zero disables the periodic reset, so Counts only resets on a state
+	// transition or a call to Reset.
+	Interval time.Duration
+
+	// CallTimeout, if non-zero, bounds each call made through
+	// ExecuteContext with a derived per-invocation deadline. A call that
+	// exceeds it fails with context.DeadlineExceeded, which counts as a
+	// failure like any other error.
+	CallTimeout time.Duration
+
+	// IsSuccessful classifies err as a success (true) or failure (false)
+	// for the purpose of tripping the breaker. If nil, any non-nil err
+	// counts as a failure.
+	IsSuccessful func(err error) bool
+
+	// OnSuccess is called whenever an admitted call succeeds. A result
+	// discarded as stale (its generation has ended) does not call it.
+	OnSuccess func()
+
+	// OnFailure is called whenever an admitted call fails. A result
+	// discarded as stale (its generation has ended) does not call it.
+	OnFailure func()
+
+	// OnReject is called whenever a call is rejected without running,
+	// with the state that caused the rejection (Open or HalfOpen).
+	OnReject func(state State)
+
 	// OnStateChange is called when the circuit breaker state changes
 	OnStateChange func(from, to State)
 }
@@ -65,43 +103,35 @@ type Settings struct {
 // DefaultSettings returns the default circuit breaker settings
 func DefaultSettings() Settings {
 	return Settings{
-		FailureThreshold: 5,
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 5
+		},
 		SuccessThreshold: 2,
 		Timeout:          30 * time.Second,
 		MaxRequests:      1,
-		OnStateChange:    nil,
+		Interval:         0,
+		IsSuccessful: func(err error) bool {
+			return err == nil
+		},
+		OnSuccess:     nil,
+		OnFailure:     nil,
+		OnReject:      nil,
+		OnStateChange: nil,
 	}
 }
 
-// circuitBreaker is the implementation of CircuitBreaker interface
+// circuitBreaker is the implementation of CircuitBreaker interface. It is a
+// thin wrapper that drives a Tracking through the func() (interface{},
+// error) call shape; Tracking itself holds all the state-machine
+// bookkeeping and can be driven directly by callers whose call sites don't
+// fit that shape.
 type circuitBreaker struct {
-	mu sync.RWMutex
-
-	// Current state of the circuit breaker
-	state State
-
-	// Number of consecutive failures
-	failureCount int
-
-	// Number of consecutive successes (used in half-open state)
-	successCount int
-
-	// Number of requests in half-open state
-	halfOpenRequests int
-
-	// Time when the circuit was opened
-	openedAt time.Time
-
-	// Configuration settings
-	settings Settings
+	*Tracking
 }
 
 // New creates a new circuit breaker with the given settings
 func New(settings Settings) CircuitBreaker {
-	return &circuitBreaker{
-		state:    StateClosed,
-		settings: settings,
-	}
+	return &circuitBreaker{Tracking: NewTracking(settings)}
 }
 
 // NewWithDefaults creates a new circuit breaker with default settings
@@ -109,97 +139,21 @@ func NewWithDefaults() CircuitBreaker {
 	return New(DefaultSettings())
 }
 
+// Unwrap returns the underlying Tracking.
+func (cb *circuitBreaker) Unwrap() *Tracking {
+	return cb.Tracking
+}
+
 // Execute runs the given function if the circuit allows it
 func (cb *circuitBreaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
-	// Phase 1: Check if we can proceed
-	cb.mu.Lock()
-
-	if cb.state == StateOpen && time.Since(cb.openedAt) >= cb.settings.Timeout {
-		cb.setState(StateHalfOpen)
-		cb.halfOpenRequests = 0
-		cb.successCount = 0
-	}
-
-	state := cb.state
-
-	switch state {
-	case StateOpen:
-		cb.mu.Unlock()
-		return nil, errors.New("circuit breaker is open")
-	case StateHalfOpen:
-		if cb.halfOpenRequests >= cb.settings.MaxRequests {
-			cb.mu.Unlock()
-			return nil, errors.New("circuit breaker is half-open")
-		}
-		cb.halfOpenRequests++
+	generation, err := cb.OnRequest()
+	if err != nil {
+		return nil, err
 	}
 
-	cb.mu.Unlock() // ✅ Unlock ก่อนเรียก fn()
-
-	// Phase 2: Execute (without lock)
 	result, err := fn()
 
-	// Phase 3: Record result
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	if err != nil {
-		cb.failureCount++
-		cb.successCount = 0
-		// ถ้า fail ใน half-open หรือถึง threshold ให้กลับไป Open
-		if state == StateHalfOpen || cb.failureCount >= cb.settings.FailureThreshold {
-			cb.setState(StateOpen)
-			cb.openedAt = time.Now()
-		}
-	} else {
-		cb.successCount++
-		cb.failureCount = 0
-		if state == StateHalfOpen {
-			cb.halfOpenRequests = 0 // Reset เพื่อให้ลองต่อได้
-			if cb.successCount >= cb.settings.SuccessThreshold {
-				cb.setState(StateClosed)
-			}
-		}
-	}
+	cb.record(generation, err)
 
 	return result, err
 }
-
-// State returns the current state of the circuit breaker
-func (cb *circuitBreaker) State() State {
-	cb.mu.RLock()
-	defer cb.mu.RUnlock()
-	return cb.state
-}
-
-// Reset resets the circuit breaker to the initial closed state
-func (cb *circuitBreaker) Reset() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
-	cb.setState(StateClosed)
-	cb.failureCount = 0
-	cb.successCount = 0
-	cb.halfOpenRequests = 0
-	cb.openedAt = time.Time{}
-}
-
-// Counts returns the current failure and success counts
-func (cb *circuitBreaker) Counts() (failures int, successes int) {
-	cb.mu.RLock()
-	defer cb.mu.RUnlock()
-	return cb.failureCount, cb.successCount
-}
-
-func (cb *circuitBreaker) setState(newState State) {
-	if cb.state == newState {
-		return
-	}
-
-	oldState := cb.state
-	cb.state = newState
-
-	if cb.settings.OnStateChange != nil {
-		cb.settings.OnStateChange(oldState, newState)
-	}
-}
@@ -0,0 +1,54 @@
+package circuitbreaker
+
+import "context"
+
+// ExecuteContext runs fn if the circuit allows it, honoring ctx
+// cancellation while fn runs and, if Settings.CallTimeout is set, a
+// derived per-call deadline. ctx.Err() is classified through
+// Settings.IsSuccessful like any other error.
+func (cb *circuitBreaker) ExecuteContext(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	generation, err := cb.OnRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx := ctx
+	if cb.settings.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, cb.settings.CallTimeout)
+		defer cancel()
+	}
+
+	type callResult struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		result, err := fn(callCtx)
+		done <- callResult{result, err}
+	}()
+
+	select {
+	case <-callCtx.Done():
+		err := callCtx.Err()
+		cb.record(generation, err)
+		return nil, err
+	case r := <-done:
+		cb.record(generation, r.err)
+		return r.result, r.err
+	}
+}
+
+// record scores a call's outcome via the breaker's IsSuccessful hook.
+func (cb *circuitBreaker) record(generation uint64, err error) {
+	if cb.isSuccessful(err) {
+		cb.OnSuccess(generation)
+	} else {
+		cb.OnFailure(generation)
+	}
+}
@@ -0,0 +1,68 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteContextSuccess(t *testing.T) {
+	cb := New(DefaultSettings())
+
+	result, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "ok" {
+		t.Fatalf("got %v, want ok", result)
+	}
+}
+
+func TestExecuteContextRejectsWhenOpen(t *testing.T) {
+	cb := New(tripOnFirstFailureSettings())
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fn should not run while the circuit is open")
+		return nil, nil
+	})
+	if err == nil {
+		t.Fatal("expected rejection while open")
+	}
+}
+
+func TestExecuteContextCallTimeoutCountsAsFailure(t *testing.T) {
+	settings := tripOnFirstFailureSettings()
+	settings.CallTimeout = 5 * time.Millisecond
+	cb := New(settings)
+
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("expected CallTimeout to count as a failure and trip the breaker, got %s", cb.State())
+	}
+}
+
+func TestExecuteContextAlreadyCanceled(t *testing.T) {
+	cb := New(DefaultSettings())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fn should not run with an already-canceled context")
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -20,7 +20,9 @@ func callExternalService() (string, error) {
 func main() {
 	// Create circuit breaker with custom settings
 	cb := circuitbreaker.New(circuitbreaker.Settings{
-		FailureThreshold: 3,               // Open after 3 failures
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 3 // Open after 3 failures
+		},
 		SuccessThreshold: 2,               // Close after 2 successes in HalfOpen
 		Timeout:          5 * time.Second, // Wait 5s before trying HalfOpen
 		MaxRequests:      1,               // Allow 1 request in HalfOpen
@@ -0,0 +1,51 @@
+// Command promhttp demonstrates exposing circuit breaker metrics on a
+// /metrics endpoint via circuitbreaker/metrics.
+package main
+
+import (
+	"circuitbreaker"
+	"circuitbreaker/metrics"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func callExternalService() (interface{}, error) {
+	if rand.Float32() < 0.5 {
+		return nil, errors.New("service unavailable")
+	}
+	return "success", nil
+}
+
+func main() {
+	cb := circuitbreaker.New(circuitbreaker.Settings{
+		Name: "payments-api",
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 3
+		},
+		SuccessThreshold: 2,
+		Timeout:          5 * time.Second,
+		MaxRequests:      1,
+	})
+	metrics.Instrument(cb, "payments-api")
+
+	http.Handle("/metrics", promhttp.Handler())
+	go func() {
+		log.Fatal(http.ListenAndServe(":2112", nil))
+	}()
+
+	for {
+		result, err := cb.Execute(callExternalService)
+		if err != nil {
+			fmt.Println("error:", err)
+		} else {
+			fmt.Println("result:", result)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,65 @@
+// Command roundtripper demonstrates wiring a circuitbreaker.Tracking into
+// an http.RoundTripper, so every outbound request through the client is
+// gated by the breaker without going through Execute.
+package main
+
+import (
+	"circuitbreaker"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// breakerTransport wraps an http.RoundTripper and drives a Tracking around
+// each request. This is the shape to copy for any call site that doesn't
+// fit func() (interface{}, error) — here it's RoundTrip(*http.Request)
+// (*http.Response, error).
+type breakerTransport struct {
+	next     http.RoundTripper
+	tracking *circuitbreaker.Tracking
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	generation, err := t.tracking.OnRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	if err != nil || resp.StatusCode >= 500 {
+		t.tracking.OnFailure(generation)
+	} else {
+		t.tracking.OnSuccess(generation)
+	}
+
+	return resp, err
+}
+
+func main() {
+	client := &http.Client{
+		Transport: &breakerTransport{
+			next: http.DefaultTransport,
+			tracking: circuitbreaker.NewTracking(circuitbreaker.Settings{
+				ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+					return counts.ConsecutiveFailures >= 3
+				},
+				SuccessThreshold: 2,
+				Timeout:          5 * time.Second,
+				MaxRequests:      1,
+				OnStateChange: func(from, to circuitbreaker.State) {
+					fmt.Printf("circuit state changed: %s -> %s\n", from, to)
+				},
+			}),
+		},
+	}
+
+	resp, err := client.Get("https://example.com")
+	if err != nil {
+		fmt.Println("request failed:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	fmt.Println("status:", resp.Status)
+}
@@ -0,0 +1,104 @@
+package circuitbreaker
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// groupShardCount controls how many independent sync.Maps a Group spreads
+// its breakers across, so that looking up one key's breaker never
+// contends with another key's in a different shard.
+const groupShardCount = 32
+
+// Group lazily creates and reuses one CircuitBreaker per key, so a single
+// client can guard calls to many downstream hosts (or a DB client many
+// shards) without the caller managing its own map of breakers.
+type Group struct {
+	// KeyTTL, if non-zero, is how long a key's breaker may go unused
+	// before Execute evicts it. Zero disables eviction.
+	KeyTTL time.Duration
+
+	settings Settings
+	shards   [groupShardCount]*groupShard
+}
+
+type groupShard struct {
+	m sync.Map // key string -> *groupEntry
+}
+
+type groupEntry struct {
+	cb       CircuitBreaker
+	lastUsed atomic.Int64 // UnixNano, updated on every Execute
+}
+
+// NewGroup creates a Group that lazily builds one breaker per key using settings.
+func NewGroup(settings Settings) *Group {
+	g := &Group{settings: settings}
+	for i := range g.shards {
+		g.shards[i] = &groupShard{}
+	}
+	return g
+}
+
+// Execute runs fn through the circuit breaker for key, creating one with
+// the Group's Settings on first use.
+func (g *Group) Execute(key string, fn func() (interface{}, error)) (interface{}, error) {
+	shard := g.shardFor(key)
+	now := time.Now()
+
+	if g.KeyTTL > 0 {
+		g.evictIdle(shard, now)
+	}
+
+	entry := g.getOrCreate(shard, key)
+	entry.lastUsed.Store(now.UnixNano())
+
+	return entry.cb.Execute(fn)
+}
+
+// Snapshot returns the current state of every breaker the Group currently
+// holds, keyed by key; breakers evicted after KeyTTL are not included.
+// Useful for dashboards/health checks.
+func (g *Group) Snapshot() map[string]State {
+	out := make(map[string]State)
+	for _, shard := range g.shards {
+		shard.m.Range(func(k, v interface{}) bool {
+			out[k.(string)] = v.(*groupEntry).cb.State()
+			return true
+		})
+	}
+	return out
+}
+
+func (g *Group) getOrCreate(shard *groupShard, key string) *groupEntry {
+	if v, ok := shard.m.Load(key); ok {
+		return v.(*groupEntry)
+	}
+
+	entry := &groupEntry{cb: New(g.settings)}
+	actual, _ := shard.m.LoadOrStore(key, entry)
+	return actual.(*groupEntry)
+}
+
+// evictIdle removes breakers in shard that have been unused for longer
+// than KeyTTL. It runs inline on Execute rather than on a background
+// goroutine, so cost is bounded by the shard's own size and there's no
+// extra lifecycle to manage.
+func (g *Group) evictIdle(shard *groupShard, now time.Time) {
+	deadline := now.Add(-g.KeyTTL)
+	shard.m.Range(func(k, v interface{}) bool {
+		entry := v.(*groupEntry)
+		if time.Unix(0, entry.lastUsed.Load()).Before(deadline) {
+			shard.m.Delete(k)
+		}
+		return true
+	})
+}
+
+func (g *Group) shardFor(key string) *groupShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return g.shards[h.Sum32()%groupShardCount]
+}
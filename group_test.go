@@ -0,0 +1,52 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroupIsolatesBreakersPerKey(t *testing.T) {
+	g := NewGroup(tripOnFirstFailureSettings())
+
+	if _, err := g.Execute("host-a", func() (interface{}, error) { return nil, errors.New("boom") }); err == nil {
+		t.Fatal("expected failure from host-a")
+	}
+
+	if state := g.Snapshot()["host-a"]; state != StateOpen {
+		t.Fatalf("expected host-a breaker Open, got %s", state)
+	}
+
+	result, err := g.Execute("host-b", func() (interface{}, error) { return "ok", nil })
+	if err != nil {
+		t.Fatalf("host-b should be unaffected by host-a's failures: %v", err)
+	}
+	if result != "ok" {
+		t.Fatalf("got %v, want ok", result)
+	}
+	if state := g.Snapshot()["host-b"]; state != StateClosed {
+		t.Fatalf("expected host-b breaker Closed, got %s", state)
+	}
+}
+
+func TestGroupEvictsIdleKeysAfterTTL(t *testing.T) {
+	g := NewGroup(tripOnFirstFailureSettings())
+	g.KeyTTL = 10 * time.Millisecond
+
+	g.Execute("host-a", func() (interface{}, error) { return nil, errors.New("boom") })
+	if state := g.Snapshot()["host-a"]; state != StateOpen {
+		t.Fatalf("expected host-a breaker Open before eviction, got %s", state)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Execute sweeps idle entries from the key's own shard before
+	// creating/reusing an entry, so a call past KeyTTL gets a fresh
+	// breaker instead of the tripped one.
+	if _, err := g.Execute("host-a", func() (interface{}, error) { return "ok", nil }); err != nil {
+		t.Fatalf("expected a fresh breaker for host-a after TTL eviction, got error: %v", err)
+	}
+	if state := g.Snapshot()["host-a"]; state != StateClosed {
+		t.Fatalf("expected host-a breaker Closed after eviction, got %s", state)
+	}
+}
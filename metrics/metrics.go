@@ -0,0 +1,83 @@
+// Package metrics instruments a circuitbreaker.CircuitBreaker with
+// Prometheus collectors.
+package metrics
+
+import (
+	"circuitbreaker"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	state = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cb_state",
+		Help: "Current circuit breaker state (0=closed, 1=open, 2=half-open).",
+	}, []string{"name"})
+
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cb_requests_total",
+		Help: "Total number of calls made through the circuit breaker, by result.",
+	}, []string{"name", "result"})
+
+	stateTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cb_state_transitions_total",
+		Help: "Total number of circuit breaker state transitions.",
+	}, []string{"name", "from", "to"})
+
+	openDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "cb_open_duration_seconds",
+		Help: "Time a circuit breaker spent Open before transitioning away.",
+	}, []string{"name"})
+)
+
+func init() {
+	prometheus.MustRegister(state, requestsTotal, stateTransitionsTotal, openDurationSeconds)
+}
+
+// unwrapper is implemented by circuitbreaker.CircuitBreaker values that
+// expose their underlying Tracking, without widening the public
+// CircuitBreaker interface to require it.
+type unwrapper interface {
+	Unwrap() *circuitbreaker.Tracking
+}
+
+// Instrument attaches Prometheus collectors to cb, labeled with name. It
+// registers hooks via cb's Tracking, so any callbacks the breaker was
+// already constructed with keep running alongside the metrics. cb must be
+// a breaker returned by circuitbreaker.New (or anything else implementing
+// unwrapper); otherwise Instrument is a no-op.
+func Instrument(cb circuitbreaker.CircuitBreaker, name string) {
+	u, ok := cb.(unwrapper)
+	if !ok {
+		return
+	}
+	tracking := u.Unwrap()
+
+	state.WithLabelValues(name).Set(float64(tracking.State()))
+
+	var openedAt time.Time
+
+	tracking.Hook(circuitbreaker.Hooks{
+		OnSuccess: func() {
+			requestsTotal.WithLabelValues(name, "success").Inc()
+		},
+		OnFailure: func() {
+			requestsTotal.WithLabelValues(name, "failure").Inc()
+		},
+		OnReject: func(circuitbreaker.State) {
+			requestsTotal.WithLabelValues(name, "rejected").Inc()
+		},
+		OnStateChange: func(from, to circuitbreaker.State) {
+			state.WithLabelValues(name).Set(float64(to))
+			stateTransitionsTotal.WithLabelValues(name, from.String(), to.String()).Inc()
+
+			if from == circuitbreaker.StateOpen {
+				openDurationSeconds.WithLabelValues(name).Observe(time.Since(openedAt).Seconds())
+			}
+			if to == circuitbreaker.StateOpen {
+				openedAt = time.Now()
+			}
+		},
+	})
+}
@@ -0,0 +1,82 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadyToTripRatio(t *testing.T) {
+	cb := New(Settings{
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.Requests >= 4 && float64(counts.TotalFailures)/float64(counts.Requests) >= 0.5
+		},
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+	})
+
+	succeeds := []bool{true, false, true, false}
+	for _, ok := range succeeds {
+		cb.Execute(func() (interface{}, error) {
+			if ok {
+				return nil, nil
+			}
+			return nil, errors.New("boom")
+		})
+	}
+
+	if cb.State() != StateOpen {
+		t.Fatalf("expected StateOpen once the failure ratio hit 50%% over 4 requests, got %s", cb.State())
+	}
+}
+
+func TestIntervalClearsCountsWhileClosed(t *testing.T) {
+	tr := NewTracking(Settings{
+		ReadyToTrip: func(Counts) bool { return false },
+		Timeout:     time.Minute,
+		MaxRequests: 1,
+		Interval:    10 * time.Millisecond,
+	})
+
+	gen, err := tr.OnRequest()
+	if err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+	tr.OnFailure(gen)
+
+	if tr.counts.TotalFailures != 1 {
+		t.Fatalf("expected 1 total failure before Interval elapses, got %d", tr.counts.TotalFailures)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := tr.OnRequest(); err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+	if tr.counts.TotalFailures != 0 {
+		t.Fatalf("expected Counts to clear once Interval elapsed, got %d total failures", tr.counts.TotalFailures)
+	}
+}
+
+var errIgnored = errors.New("ignored")
+
+func TestIsSuccessfulClassifiesErrorAsSuccess(t *testing.T) {
+	cb := New(Settings{
+		ReadyToTrip:      func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, errIgnored)
+		},
+	})
+
+	_, err := cb.Execute(func() (interface{}, error) { return nil, errIgnored })
+	if !errors.Is(err, errIgnored) {
+		t.Fatalf("expected errIgnored to propagate, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("expected IsSuccessful to keep the breaker Closed, got %s", cb.State())
+	}
+}
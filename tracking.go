@@ -0,0 +1,295 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Counts tracks the requests observed in the current generation. A new
+// generation starts on every state transition and, while Closed, whenever
+// Settings.Interval elapses (and on Reset), so Counts covers only the
+// requests since the current generation began.
+type Counts struct {
+	Requests             uint32
+	TotalSuccesses       uint32
+	TotalFailures        uint32
+	ConsecutiveSuccesses uint32
+	ConsecutiveFailures  uint32
+}
+
+func (c *Counts) clear() {
+	*c = Counts{}
+}
+
+// Tracking holds the circuit breaker's state-machine bookkeeping (state,
+// counts, generation, transition rules), independent of how a call is
+// invoked. circuitBreaker and typedCircuitBreaker[T] both embed a Tracking;
+// it can also be driven directly via OnRequest/OnSuccess/OnFailure.
+type Tracking struct {
+	mu sync.RWMutex
+
+	// Current state of the circuit breaker
+	state State
+
+	// counts tracked for the current generation
+	counts Counts
+
+	// generation is bumped on every state transition and Interval expiry;
+	// a result reported against a stale generation (the breaker
+	// transitioned while the call was in flight) is discarded.
+	generation uint64
+
+	// expiry is when the current generation ends: for Open it's when the
+	// breaker may move to HalfOpen, for Closed (with Interval set) it's
+	// when Counts next clears. Zero means "never".
+	expiry time.Time
+
+	// Configuration settings
+	settings Settings
+}
+
+// NewTracking creates a Tracking in the Closed state with the given settings.
+func NewTracking(settings Settings) *Tracking {
+	t := &Tracking{state: StateClosed, settings: settings}
+	t.toNewGeneration(time.Now())
+	return t
+}
+
+// Hooks is a set of event callbacks to merge into a Tracking's Settings via
+// Hook, for observers (e.g. circuitbreaker/metrics) that want to listen
+// alongside whatever callbacks the breaker was already constructed with.
+type Hooks struct {
+	OnSuccess     func()
+	OnFailure     func()
+	OnReject      func(state State)
+	OnStateChange func(from, to State)
+}
+
+// Hook chains each non-nil callback in h onto the Tracking's existing
+// Settings callbacks, so multiple observers can coexist: whatever was
+// already configured still runs, followed by h's callback.
+func (t *Tracking) Hook(h Hooks) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if h.OnSuccess != nil {
+		prev := t.settings.OnSuccess
+		t.settings.OnSuccess = func() {
+			if prev != nil {
+				prev()
+			}
+			h.OnSuccess()
+		}
+	}
+	if h.OnFailure != nil {
+		prev := t.settings.OnFailure
+		t.settings.OnFailure = func() {
+			if prev != nil {
+				prev()
+			}
+			h.OnFailure()
+		}
+	}
+	if h.OnReject != nil {
+		prev := t.settings.OnReject
+		t.settings.OnReject = func(state State) {
+			if prev != nil {
+				prev(state)
+			}
+			h.OnReject(state)
+		}
+	}
+	if h.OnStateChange != nil {
+		prev := t.settings.OnStateChange
+		t.settings.OnStateChange = func(from, to State) {
+			if prev != nil {
+				prev(from, to)
+			}
+			h.OnStateChange(from, to)
+		}
+	}
+}
+
+// readyToTrip returns the configured ReadyToTrip, or the default rule if
+// the caller left it nil.
+func (t *Tracking) readyToTrip(counts Counts) bool {
+	if t.settings.ReadyToTrip != nil {
+		return t.settings.ReadyToTrip(counts)
+	}
+	return counts.ConsecutiveFailures >= 5
+}
+
+// isSuccessful returns the configured IsSuccessful, or the default rule
+// (any non-nil err is a failure) if the caller left it nil.
+func (t *Tracking) isSuccessful(err error) bool {
+	if t.settings.IsSuccessful != nil {
+		return t.settings.IsSuccessful(err)
+	}
+	return err == nil
+}
+
+// currentState advances the state machine for the passage of time (Open's
+// Timeout elapsing, or Closed's Interval elapsing) and returns the
+// resulting state and generation.
+func (t *Tracking) currentState(now time.Time) (State, uint64) {
+	switch t.state {
+	case StateClosed:
+		if !t.expiry.IsZero() && t.expiry.Before(now) {
+			t.toNewGeneration(now)
+		}
+	case StateOpen:
+		if t.expiry.Before(now) {
+			t.setState(StateHalfOpen, now)
+		}
+	}
+	return t.state, t.generation
+}
+
+// OnRequest checks whether a call is allowed to proceed and, if so,
+// returns the generation it was admitted under. That generation must be
+// passed back into OnSuccess or OnFailure so a result from a stale
+// generation is discarded instead of scored against a breaker that has
+// since moved on.
+func (t *Tracking) OnRequest() (generation uint64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	state, generation := t.currentState(now)
+
+	switch state {
+	case StateOpen:
+		t.reject(state)
+		return generation, errors.New("circuit breaker is open")
+	case StateHalfOpen:
+		// MaxRequests bounds the probes in flight at once; each success
+		// frees its slot so the breaker can go on to reach SuccessThreshold.
+		if t.counts.Requests-t.counts.TotalSuccesses >= uint32(t.settings.MaxRequests) {
+			t.reject(state)
+			return generation, errors.New("circuit breaker is half-open")
+		}
+	}
+
+	t.counts.Requests++
+	return generation, nil
+}
+
+func (t *Tracking) reject(state State) {
+	if t.settings.OnReject != nil {
+		t.settings.OnReject(state)
+	}
+}
+
+// OnSuccess reports that the call admitted under generation succeeded.
+func (t *Tracking) OnSuccess(generation uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	state, current := t.currentState(now)
+	if current != generation {
+		return
+	}
+
+	t.counts.TotalSuccesses++
+	t.counts.ConsecutiveSuccesses++
+	t.counts.ConsecutiveFailures = 0
+
+	if state == StateHalfOpen && t.counts.ConsecutiveSuccesses >= uint32(t.settings.SuccessThreshold) {
+		t.setState(StateClosed, now)
+	}
+
+	if t.settings.OnSuccess != nil {
+		t.settings.OnSuccess()
+	}
+}
+
+// OnFailure reports that the call admitted under generation failed.
+func (t *Tracking) OnFailure(generation uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	state, current := t.currentState(now)
+	if current != generation {
+		return
+	}
+
+	t.counts.TotalFailures++
+	t.counts.ConsecutiveFailures++
+	t.counts.ConsecutiveSuccesses = 0
+
+	switch state {
+	case StateClosed:
+		if t.readyToTrip(t.counts) {
+			t.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		t.setState(StateOpen, now)
+	}
+
+	if t.settings.OnFailure != nil {
+		t.settings.OnFailure()
+	}
+}
+
+// State returns the current state of the circuit breaker
+func (t *Tracking) State() State {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.state
+}
+
+// Reset resets the circuit breaker to the initial closed state,
+// unconditionally clearing counts, starting a new generation and resetting
+// expiry even if it was already Closed.
+func (t *Tracking) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.state = StateClosed
+	t.toNewGeneration(time.Now())
+}
+
+// Counts returns the current consecutive failure and success counts
+func (t *Tracking) Counts() (failures int, successes int) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return int(t.counts.ConsecutiveFailures), int(t.counts.ConsecutiveSuccesses)
+}
+
+func (t *Tracking) setState(newState State, now time.Time) {
+	if t.state == newState {
+		return
+	}
+
+	oldState := t.state
+	t.state = newState
+
+	t.toNewGeneration(now)
+
+	if t.settings.OnStateChange != nil {
+		t.settings.OnStateChange(oldState, newState)
+	}
+}
+
+// toNewGeneration clears Counts, bumps generation, and sets the expiry for
+// whatever state the breaker is now in.
+func (t *Tracking) toNewGeneration(now time.Time) {
+	t.generation++
+	t.counts.clear()
+
+	switch t.state {
+	case StateClosed:
+		if t.settings.Interval == 0 {
+			t.expiry = time.Time{}
+		} else {
+			t.expiry = now.Add(t.settings.Interval)
+		}
+	case StateOpen:
+		t.expiry = now.Add(t.settings.Timeout)
+	default: // StateHalfOpen
+		t.expiry = time.Time{}
+	}
+}
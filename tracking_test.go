@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackingOpensAfterConsecutiveFailures(t *testing.T) {
+	tr := NewTracking(Settings{
+		ReadyToTrip:      func(counts Counts) bool { return counts.ConsecutiveFailures >= 2 },
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+	})
+
+	for i := 0; i < 2; i++ {
+		gen, err := tr.OnRequest()
+		if err != nil {
+			t.Fatalf("request %d: unexpected rejection: %v", i, err)
+		}
+		tr.OnFailure(gen)
+	}
+
+	if tr.State() != StateOpen {
+		t.Fatalf("expected StateOpen after 2 consecutive failures, got %s", tr.State())
+	}
+	if _, err := tr.OnRequest(); err == nil {
+		t.Fatal("expected OnRequest to reject while Open")
+	}
+}
+
+func TestTrackingDiscardsStaleGeneration(t *testing.T) {
+	tr := NewTracking(Settings{
+		ReadyToTrip:      func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+	})
+
+	staleGen, err := tr.OnRequest()
+	if err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+
+	// A second call fails and trips the breaker into a new generation
+	// before the first call (staleGen) reports its own outcome.
+	gen2, err := tr.OnRequest()
+	if err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+	tr.OnFailure(gen2)
+
+	if tr.State() != StateOpen {
+		t.Fatalf("expected StateOpen, got %s", tr.State())
+	}
+
+	tr.OnSuccess(staleGen) // stale: must be discarded, not resurrect the breaker
+
+	if tr.State() != StateOpen {
+		t.Fatalf("expected the stale OnSuccess to be discarded and the breaker to stay Open, got %s", tr.State())
+	}
+}
+
+func TestTrackingResetClearsCountsWhileAlreadyClosed(t *testing.T) {
+	tr := NewTracking(Settings{
+		ReadyToTrip:      func(counts Counts) bool { return counts.ConsecutiveFailures >= 5 },
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+	})
+
+	for i := 0; i < 2; i++ {
+		gen, err := tr.OnRequest()
+		if err != nil {
+			t.Fatalf("request %d: unexpected rejection: %v", i, err)
+		}
+		tr.OnFailure(gen)
+	}
+	if failures, _ := tr.Counts(); failures != 2 {
+		t.Fatalf("expected 2 consecutive failures before Reset, got %d", failures)
+	}
+
+	tr.Reset() // still Closed: must clear counts, not early-return as a no-op transition
+
+	if tr.State() != StateClosed {
+		t.Fatalf("expected StateClosed after Reset, got %s", tr.State())
+	}
+	if failures, successes := tr.Counts(); failures != 0 || successes != 0 {
+		t.Fatalf("expected Counts cleared after Reset, got failures=%d successes=%d", failures, successes)
+	}
+}
+
+func TestTrackingHookChainsWithExistingCallback(t *testing.T) {
+	var calls []string
+	tr := NewTracking(Settings{
+		ReadyToTrip:      func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+		OnStateChange: func(from, to State) {
+			calls = append(calls, "settings:"+to.String())
+		},
+	})
+	tr.Hook(Hooks{
+		OnStateChange: func(from, to State) {
+			calls = append(calls, "hook:"+to.String())
+		},
+	})
+
+	gen, err := tr.OnRequest()
+	if err != nil {
+		t.Fatalf("unexpected rejection: %v", err)
+	}
+	tr.OnFailure(gen)
+
+	want := []string{"settings:open", "hook:open"}
+	if len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("expected both callbacks to run in order, got %v", calls)
+	}
+}
@@ -0,0 +1,48 @@
+package circuitbreaker
+
+// TypedCircuitBreaker is the generic counterpart to CircuitBreaker: Execute
+// returns a T instead of an interface{}, so callers don't need a type
+// assertion to get their result back.
+type TypedCircuitBreaker[T any] interface {
+	// Execute runs the given function if the circuit allows it
+	Execute(fn func() (T, error)) (T, error)
+
+	// State returns the current state of the circuit breaker
+	State() State
+
+	// Reset resets the circuit breaker to the initial closed state
+	Reset()
+
+	// Counts returns the current failure and success counts
+	Counts() (failures int, successes int)
+}
+
+// typedCircuitBreaker is the implementation of TypedCircuitBreaker[T]. It
+// shares its state machine with circuitBreaker via Tracking.
+type typedCircuitBreaker[T any] struct {
+	*Tracking
+}
+
+// NewTyped creates a new generic circuit breaker with the given settings.
+func NewTyped[T any](settings Settings) TypedCircuitBreaker[T] {
+	return &typedCircuitBreaker[T]{Tracking: NewTracking(settings)}
+}
+
+// Execute runs the given function if the circuit allows it
+func (cb *typedCircuitBreaker[T]) Execute(fn func() (T, error)) (T, error) {
+	generation, err := cb.OnRequest()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	result, err := fn()
+
+	if cb.isSuccessful(err) {
+		cb.OnSuccess(generation)
+	} else {
+		cb.OnFailure(generation)
+	}
+
+	return result, err
+}
@@ -0,0 +1,110 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func tripOnFirstFailureSettings() Settings {
+	return Settings{
+		ReadyToTrip:      func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+		SuccessThreshold: 1,
+		Timeout:          time.Minute,
+		MaxRequests:      1,
+	}
+}
+
+func TestNewTypedZeroValueOnOpenRejection(t *testing.T) {
+	cb := NewTyped[int](tripOnFirstFailureSettings())
+
+	if _, err := cb.Execute(func() (int, error) { return 0, errors.New("boom") }); err == nil {
+		t.Fatal("expected the first call to fail and trip the breaker")
+	}
+
+	result, err := cb.Execute(func() (int, error) { return 42, nil })
+	if err == nil {
+		t.Fatal("expected rejection while the breaker is open")
+	}
+	if result != 0 {
+		t.Fatalf("expected zero value 0 on rejection, got %d", result)
+	}
+}
+
+func TestNewTypedZeroValueOnHalfOpenRejection(t *testing.T) {
+	settings := tripOnFirstFailureSettings()
+	settings.Timeout = 10 * time.Millisecond
+	cb := NewTyped[string](settings)
+
+	if _, err := cb.Execute(func() (string, error) { return "", errors.New("boom") }); err == nil {
+		t.Fatal("expected the first call to fail and trip the breaker")
+	}
+
+	time.Sleep(20 * time.Millisecond) // let Timeout elapse so the next call is admitted into HalfOpen
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go cb.Execute(func() (string, error) {
+		close(started)
+		<-release
+		return "ok", nil
+	})
+	<-started
+	defer close(release)
+
+	result, err := cb.Execute(func() (string, error) {
+		t.Fatal("fn should not run once MaxRequests is exhausted in HalfOpen")
+		return "unreachable", nil
+	})
+	if err == nil {
+		t.Fatal("expected rejection once HalfOpen's MaxRequests is exhausted")
+	}
+	if result != "" {
+		t.Fatalf("expected zero value \"\" on rejection, got %q", result)
+	}
+}
+
+func TestNewTypedPointerPropagation(t *testing.T) {
+	cb := NewTyped[*int](DefaultSettings())
+
+	want := 7
+	result, err := cb.Execute(func() (*int, error) { return &want, nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != &want {
+		t.Fatalf("expected the same pointer back, got %p want %p", result, &want)
+	}
+}
+
+type widget struct {
+	Name string
+	Qty  int
+}
+
+func TestNewTypedStructPropagation(t *testing.T) {
+	cb := NewTyped[widget](DefaultSettings())
+
+	want := widget{Name: "bolt", Qty: 3}
+	result, err := cb.Execute(func() (widget, error) { return want, nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != want {
+		t.Fatalf("got %+v, want %+v", result, want)
+	}
+}
+
+func TestNewTypedSlicePropagation(t *testing.T) {
+	cb := NewTyped[[]string](DefaultSettings())
+
+	want := []string{"a", "b", "c"}
+	result, err := cb.Execute(func() ([]string, error) { return want, nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("got %v, want %v", result, want)
+	}
+}